@@ -0,0 +1,37 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/affine"
+)
+
+// ColorM represents a matrix to transform coloring when rendering an image.
+//
+// The initial (zero) value is an identity matrix, which doesn't change any
+// color.
+type ColorM struct {
+	impl *affine.ColorM
+}
+
+// Translate adds (r, g, b, a) to the color that results from applying the
+// ColorM's existing transform.
+func (c *ColorM) Translate(r, g, b, a float64) {
+	if c.impl == nil {
+		c.impl = &affine.ColorM{}
+		c.impl.Reset()
+	}
+	c.impl.Translate(r, g, b, a)
+}
@@ -0,0 +1,38 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+// CompositeMode represents the composition (blending) mode used when
+// drawing one image onto another, matching the Porter-Duff compositing
+// operators.
+type CompositeMode int
+
+const (
+	// CompositeModeSourceOver is the regular alpha blending: the source is
+	// composited over the destination.
+	CompositeModeSourceOver CompositeMode = iota
+	CompositeModeClear
+	CompositeModeCopy
+	CompositeModeDestination
+	CompositeModeDestinationOver
+	CompositeModeSourceIn
+	CompositeModeDestinationIn
+	CompositeModeSourceOut
+	CompositeModeDestinationOut
+	CompositeModeSourceAtop
+	CompositeModeDestinationAtop
+	CompositeModeXor
+	CompositeModeLighter
+)
@@ -0,0 +1,69 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+// GeoM represents a matrix to transform geometry when rendering an image.
+//
+// The initial (zero) value is an identity matrix, which draws the image at
+// (0, 0) without scaling or rotation.
+type GeoM struct {
+	a, b, c, d, tx, ty float64
+	initialized        bool
+}
+
+func (g *GeoM) init() {
+	if g.initialized {
+		return
+	}
+	g.a, g.d = 1, 1
+	g.initialized = true
+}
+
+// Scale scales the matrix by (sx, sy).
+func (g *GeoM) Scale(sx, sy float64) {
+	g.init()
+	g.a *= sx
+	g.b *= sx
+	g.c *= sy
+	g.d *= sy
+}
+
+// Translate translates the matrix by (tx, ty).
+func (g *GeoM) Translate(tx, ty float64) {
+	g.init()
+	g.tx += tx
+	g.ty += ty
+}
+
+// Concat multiplies a geometry matrix other with the receiver, so that the
+// receiver's existing transform is applied first and other is applied to
+// the result.
+func (g *GeoM) Concat(other GeoM) {
+	g.init()
+	other.init()
+	a := other.a*g.a + other.c*g.b
+	b := other.b*g.a + other.d*g.b
+	c := other.a*g.c + other.c*g.d
+	d := other.b*g.c + other.d*g.d
+	tx := other.a*g.tx + other.c*g.ty + other.tx
+	ty := other.b*g.tx + other.d*g.ty + other.ty
+	g.a, g.b, g.c, g.d, g.tx, g.ty = a, b, c, d, tx, ty
+}
+
+// elements returns the matrix's six affine components.
+func (g *GeoM) elements() (a, b, c, d, tx, ty float64) {
+	g.init()
+	return g.a, g.b, g.c, g.d, g.tx, g.ty
+}
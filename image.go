@@ -137,6 +137,8 @@ func (i *Image) fill(r, g, b, a uint8) {
 //   * All ColorM values are same
 //   * All CompositeMode values are same
 //   * All Filter values are same
+//   * All Shader values are same (nil counts as a value)
+//   * When Shader is not nil, all Uniforms values are same
 //
 // For more performance tips, see https://github.com/hajimehoshi/ebiten/wiki/Performance-Tips.
 func (i *Image) DrawImage(img *Image, options *DrawImageOptions) {
@@ -183,8 +185,95 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) {
 
 	mode := opengl.CompositeMode(options.CompositeMode)
 
+	var shader *shareable.Shader
+	if options.Shader != nil {
+		shader = options.Shader.shareableShader
+	}
+
 	a, b, c, d, tx, ty := geom.elements()
-	i.shareableImage.DrawImage(img.shareableImage, sx0, sy0, sx1, sy1, a, b, c, d, tx, ty, options.ColorM.impl, mode, graphics.Filter(options.Filter))
+	i.shareableImage.DrawImage(img.shareableImage, sx0, sy0, sx1, sy1, a, b, c, d, tx, ty, options.ColorM.impl, mode, graphics.Filter(options.Filter), shader, options.Uniforms)
+}
+
+// A DrawTrianglesOptions represents options to render triangles on an image.
+type DrawTrianglesOptions struct {
+	// ColorM is a color matrix to draw.
+	// The default (zero) value is identity, which doesn't change any color.
+	ColorM ColorM
+
+	// CompositeMode is a composite mode to draw.
+	// The default (zero) value is regular alpha blending.
+	CompositeMode CompositeMode
+
+	// Filter is a type of texture filter.
+	// The default (zero) value is FilterNearest.
+	Filter Filter
+}
+
+// DrawTriangles draws triangles with the specified vertices and their
+// indices.
+//
+// If len(indices) is not a multiple of 3, DrawTriangles panics.
+//
+// Each element in indices must be a number within the range of [0, len(vertices)),
+// or DrawTriangles panics.
+//
+// The vertex position is determined by DstX and DstY, not by the GeoM used by
+// DrawImage. The source position is in texels of src, determined by SrcX and
+// SrcY of each vertex, not by the SourceRect used by DrawImage.
+//
+// DrawTriangles is useful for effects like distortion, sprite skewing,
+// tilemap batching, particle systems, or simple 2.5D projections, none of
+// which the axis-aligned-plus-affine DrawImage can express.
+//
+// When the image i is disposed, DrawTriangles does nothing.
+// When the given image src is disposed, DrawTriangles panics.
+//
+// When the given image is as same as i, DrawTriangles panics.
+//
+// DrawTriangles works more efficiently as batches when the successive calls
+// of DrawTriangles satisfy the same conditions as DrawImage's batching
+// conditions, with vertices/indices accumulated instead of quads, and a
+// flush triggered by the same state changes (source image, ColorM,
+// CompositeMode, and Filter).
+func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, src *Image, options *DrawTrianglesOptions) {
+	i.copyCheck()
+	if src.isDisposed() {
+		panic("ebiten: the given image to DrawTriangles must not be disposed")
+	}
+	if i.isDisposed() {
+		return
+	}
+	if i.shareableImage == src.shareableImage {
+		panic("ebiten: src must be different from the receiver")
+	}
+	if len(indices)%3 != 0 {
+		panic("ebiten: len(indices) must be a multiple of 3")
+	}
+	for _, idx := range indices {
+		if int(idx) >= len(vertices) {
+			panic("ebiten: indices must be within the range of vertices")
+		}
+	}
+
+	if options == nil {
+		options = &DrawTrianglesOptions{}
+	}
+
+	mode := opengl.CompositeMode(options.CompositeMode)
+
+	vs := make([]float32, len(vertices)*8)
+	for idx, v := range vertices {
+		vs[idx*8] = v.DstX
+		vs[idx*8+1] = v.DstY
+		vs[idx*8+2] = v.SrcX
+		vs[idx*8+3] = v.SrcY
+		vs[idx*8+4] = v.ColorR
+		vs[idx*8+5] = v.ColorG
+		vs[idx*8+6] = v.ColorB
+		vs[idx*8+7] = v.ColorA
+	}
+
+	i.shareableImage.DrawTriangles(src.shareableImage, vs, indices, options.ColorM.impl, mode, graphics.Filter(options.Filter))
 }
 
 // Bounds returns the bounds of the image.
@@ -200,7 +289,10 @@ func (i *Image) ColorModel() color.Model {
 
 // At returns the color of the image at (x, y).
 //
-// At loads pixels from GPU to system memory if necessary, which means that At can be slow.
+// At reads through the same readback as Pixels: every call copies all of
+// the image's pixels and queues one readback of them. The result is not
+// cached between calls, so each call to At costs as much as a call to
+// Pixels, whether or not the image changed in between.
 //
 // At always returns a transparent color if the image is disposed.
 //
@@ -215,6 +307,23 @@ func (i *Image) At(x, y int) color.Color {
 	return i.shareableImage.At(x, y)
 }
 
+// Pixels returns a copy of all the pixels of the image, in the same
+// alpha-premultiplied RGBA order as ReplacePixels expects.
+//
+// Pixels shares the same asynchronous readback as At: reading the whole
+// image at once with Pixels is far cheaper than calling At in a loop,
+// since only one readback is queued instead of one per pixel.
+//
+// Pixels returns nil if the image is disposed.
+//
+// Pixels can't be called before the main loop (ebiten.Run) starts.
+func (i *Image) Pixels() []byte {
+	if i.isDisposed() {
+		return nil
+	}
+	return i.shareableImage.Pixels()
+}
+
 // Dispose disposes the image data. After disposing, most of image functions do nothing and returns meaningless values.
 //
 // Dispose is useful to save memory.
@@ -234,7 +343,14 @@ func (i *Image) Dispose() {
 //
 // The given p must represent RGBA pre-multiplied alpha values. len(p) must equal to 4 * (image width) * (image height).
 //
-// ReplacePixels may be slow (as for implementation, this calls glTexSubImage2D).
+// ReplacePixels queues the upload and returns without waiting for it to
+// complete (as for implementation, this copies p into a ring of pixel
+// buffer objects and calls glTexSubImage2D asynchronously). The caller is
+// free to reuse p immediately after ReplacePixels returns, since p is
+// copied, not retained. Calling ReplacePixels on the same image many
+// times per frame, e.g. to stream video frames or generate procedural
+// textures, is therefore cheaper than it used to be; the ring reuses its
+// buffers in turn and does not stall when all of them have been used.
 //
 // When len(p) is not appropriate, ReplacePixels panics.
 //
@@ -325,6 +441,27 @@ type DrawImageOptions struct {
 	// Filter is a type of texture filter.
 	// The default (zero) value is FilterNearest.
 	Filter Filter
+
+	// Shader is a custom fragment shader to use instead of the regular
+	// texture-and-ColorM pipeline.
+	//
+	// The default (zero) value is nil, which uses the regular pipeline.
+	//
+	// Successive DrawImage calls with the same Shader (and the same
+	// Uniforms values) can be batched together. Switching Shader, like
+	// switching CompositeMode or Filter, flushes the current batch.
+	//
+	// See the Shader documentation: running the compiled program per
+	// fragment isn't implemented yet, so binding a Shader currently only
+	// affects batching.
+	Shader *Shader
+
+	// Uniforms is a set of uniform variables to pass to Shader.
+	// Uniforms is ignored when Shader is nil.
+	//
+	// The accepted value types are float64, []float64 and [][]float64,
+	// matching the scalar, vector and matrix uniforms a shader can declare.
+	Uniforms map[string]interface{}
 }
 
 // NewImage returns an empty image.
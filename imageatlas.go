@@ -0,0 +1,74 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+	"runtime"
+
+	"github.com/hajimehoshi/ebiten/internal/graphicsutil"
+	"github.com/hajimehoshi/ebiten/internal/packing"
+)
+
+// ImageAtlas packs multiple images into the regions of a single shared
+// backing texture.
+//
+// shareable.Image already shares textures opportunistically, but there is
+// no guarantee that two particular images end up co-located. ImageAtlas
+// gives that guarantee: every image added to the same ImageAtlas is
+// backed by the same texture, so DrawImage calls between them can always
+// be batched into a single draw call. This is useful for loading a whole
+// spritesheet at once without manually slicing it via SourceRect.
+//
+// The zero value of ImageAtlas is not available to use. Use NewImageAtlas
+// to create an ImageAtlas.
+type ImageAtlas struct {
+	packer *packing.Page
+	image  *Image
+}
+
+// NewImageAtlas creates a new ImageAtlas backed by a texture of the given
+// size.
+//
+// If width or height is less than 1 or more than device-dependent maximum
+// size, NewImageAtlas panics.
+func NewImageAtlas(width, height int) *ImageAtlas {
+	return &ImageAtlas{
+		packer: packing.NewPage(width, height),
+		image:  NewImage(width, height),
+	}
+}
+
+// Add packs src into a free region of the atlas and returns a sub-image of
+// the atlas's shared texture with src's content.
+//
+// If src no longer fits into the atlas's remaining free space, Add panics.
+func (a *ImageAtlas) Add(src image.Image) *Image {
+	size := src.Bounds().Size()
+	node := a.packer.Alloc(size.X, size.Y)
+	if node == nil {
+		panic("ebiten: the ImageAtlas is full")
+	}
+
+	x, y, w, h := node.Region()
+	sub := &Image{
+		shareableImage: a.image.shareableImage.SubImage(x, y, w, h),
+	}
+	sub.addr = sub
+	runtime.SetFinalizer(sub, (*Image).Dispose)
+
+	sub.ReplacePixels(graphicsutil.CopyImage(src))
+	return sub
+}
@@ -0,0 +1,114 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package affine offers matrix types used for coloring and geometry
+// transformations.
+package affine
+
+// ColorM represents a matrix to transform coloring when rendering an image.
+//
+// ColorM is applied to the straight alpha color while an Image's pixels
+// are alpha premultiplied: Apply un-multiplies the color before applying
+// the matrix, and multiplies the result by its alpha again afterwards.
+//
+// The element is represented like this:
+//
+//	a0 a1 a2 a3 a4
+//	b0 b1 b2 b3 b4
+//	c0 c1 c2 c3 c4
+//	d0 d1 d2 d3 d4
+type ColorM struct {
+	// elements represents a matrix and a translation vector affecting
+	// the rendering color in a row-major format.
+	elements [4][5]float64
+
+	// identity represents whether this is an identity matrix.
+	identity bool
+}
+
+// IsIdentity returns a boolean indicating whether the ColorM is an identity
+// matrix that doesn't change any color.
+func (c *ColorM) IsIdentity() bool {
+	return c == nil || c.identity
+}
+
+// Reset resets the ColorM to the identity matrix.
+func (c *ColorM) Reset() {
+	c.elements = [4][5]float64{}
+	c.elements[0][0] = 1
+	c.elements[1][1] = 1
+	c.elements[2][2] = 1
+	c.elements[3][3] = 1
+	c.identity = true
+}
+
+// Translate adds (r, g, b, a) to the color that results from applying c's
+// existing transform, and stores the result back into c.
+func (c *ColorM) Translate(r, g, b, a float64) {
+	if c.IsIdentity() {
+		c.Reset()
+	}
+	c.elements[0][4] += r
+	c.elements[1][4] += g
+	c.elements[2][4] += b
+	c.elements[3][4] += a
+	c.identity = false
+}
+
+// Elements returns the matrix and the translation vector elements.
+func (c *ColorM) Elements() [4][5]float64 {
+	if c == nil {
+		return identityColorM.elements
+	}
+	return c.elements
+}
+
+var identityColorM = ColorM{
+	elements: [4][5]float64{
+		{1, 0, 0, 0, 0},
+		{0, 1, 0, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 0, 1, 0},
+	},
+	identity: true,
+}
+
+// Value returns the ColorM pointed to by c, or the identity ColorM if c is
+// nil. Unlike a pointer, the returned value is directly comparable with
+// ==, which the batching logic in internal/shareable relies on to decide
+// whether two draws use the same ColorM.
+func (c *ColorM) Value() ColorM {
+	if c == nil {
+		return identityColorM
+	}
+	return *c
+}
+
+// Apply applies the ColorM to a premultiplied-alpha RGBA color and returns
+// the resulting premultiplied-alpha RGBA color.
+func (c *ColorM) Apply(r, g, b, a float64) (r2, g2, b2, a2 float64) {
+	if c.IsIdentity() {
+		return r, g, b, a
+	}
+	if a > 0 {
+		r, g, b = r/a, g/a, b/a
+	}
+	e := c.elements
+	r2 = e[0][0]*r + e[0][1]*g + e[0][2]*b + e[0][3]*a + e[0][4]
+	g2 = e[1][0]*r + e[1][1]*g + e[1][2]*b + e[1][3]*a + e[1][4]
+	b2 = e[2][0]*r + e[2][1]*g + e[2][2]*b + e[2][3]*a + e[2][4]
+	a2 = e[3][0]*r + e[3][1]*g + e[3][2]*b + e[3][3]*a + e[3][4]
+	r2, g2, b2 = r2*a2, g2*a2, b2*a2
+	return
+}
@@ -0,0 +1,31 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graphics provides the constants and the low-level data layout
+// shared by the rendering backends.
+package graphics
+
+// Filter represents the type of texture filter to be used when an image is
+// magnified or minified.
+type Filter int
+
+const (
+	FilterNearest Filter = iota
+	FilterLinear
+)
+
+// VertexFloatNum is the number of float32 values that represent a single
+// vertex passed down to a backend: the destination position, the source
+// (texture) position, and the four color-scale channels.
+const VertexFloatNum = 8
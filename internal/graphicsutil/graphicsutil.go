@@ -0,0 +1,37 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graphicsutil offers utility functions used to convert between
+// image.Image and the raw alpha-premultiplied RGBA byte slices the
+// renderer works with.
+package graphicsutil
+
+import (
+	"image"
+	"image/draw"
+)
+
+// CopyImage copies the pixels of src into a fresh alpha-premultiplied RGBA
+// byte slice, in the row-major order ReplacePixels expects.
+func CopyImage(src image.Image) []byte {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Stride == w*4 {
+		return append([]byte(nil), rgba.Pix[:4*w*h]...)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst.Pix
+}
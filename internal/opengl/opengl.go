@@ -0,0 +1,41 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package opengl wraps the OpenGL calls that the rest of ebiten relies on:
+// texture and framebuffer management, shader program compilation and
+// caching, and pixel buffer objects for asynchronous CPU<->GPU transfers.
+package opengl
+
+// CompositeMode represents the blending of the source color and the
+// destination color when drawing.
+type CompositeMode int
+
+const (
+	CompositeModeSourceOver CompositeMode = iota
+	CompositeModeClear
+	CompositeModeCopy
+	CompositeModeDestination
+	CompositeModeDestinationOver
+	CompositeModeSourceIn
+	CompositeModeDestinationIn
+	CompositeModeSourceOut
+	CompositeModeDestinationOut
+	CompositeModeSourceAtop
+	CompositeModeDestinationAtop
+	CompositeModeXor
+	CompositeModeLighter
+)
+
+// TextureID identifies a texture allocated on the GPU.
+type TextureID int
@@ -0,0 +1,115 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import "sync"
+
+// pboRingSize is the number of pixel buffer objects kept in each ring.
+// glTexSubImage2D (upload) and glReadPixels (readback) are queued against
+// a free buffer in the ring; as long as a caller doesn't touch the same
+// texture faster than the ring can drain, the GPU command never blocks
+// the CPU.
+const pboRingSize = 3
+
+// PixelBuffer is a single pixel buffer object slot in a PixelBufferRing.
+type PixelBuffer struct {
+	id      uint32
+	pending bool
+	data    []byte
+}
+
+// PixelBufferRing is a small ring of pixel buffer objects used to pipeline
+// CPU<->GPU pixel transfers for a single texture: ReplacePixels queues an
+// upload into the next buffer and returns immediately, and Pixels (or At)
+// queues a readback into the next buffer. Buffers are used in turn; a
+// buffer is never left pending between calls, so neither call blocks on an
+// exhausted ring.
+type PixelBufferRing struct {
+	mu      sync.Mutex
+	buffers [pboRingSize]PixelBuffer
+	next    int
+
+	uploadFn   func(buf *PixelBuffer, p []byte)
+	readbackFn func(buf *PixelBuffer, p []byte) []byte
+}
+
+// NewPixelBufferRing creates a ring of pixel buffer objects. uploadFn and
+// readbackFn are the seams to the actual glBufferData/glTexSubImage2D and
+// glReadPixels/glMapBuffer calls; passing nil installs an in-memory
+// implementation, which is what the software driver (and tests) use.
+func NewPixelBufferRing(uploadFn func(buf *PixelBuffer, p []byte), readbackFn func(buf *PixelBuffer, p []byte) []byte) *PixelBufferRing {
+	r := &PixelBufferRing{uploadFn: uploadFn, readbackFn: readbackFn}
+	if r.uploadFn == nil {
+		r.uploadFn = func(buf *PixelBuffer, p []byte) {
+			buf.data = append([]byte(nil), p...)
+		}
+	}
+	if r.readbackFn == nil {
+		r.readbackFn = func(buf *PixelBuffer, p []byte) []byte {
+			buf.data = p
+			return buf.data
+		}
+	}
+	return r
+}
+
+// QueueUpload copies p into the next free pixel buffer and queues an
+// asynchronous upload. QueueUpload never blocks: p is copied before
+// QueueUpload returns, so the caller can reuse or mutate p immediately.
+//
+// The buffer is marked pending only while uploadFn runs and is free again
+// when QueueUpload returns; QueueUpload does not check or wait on the
+// pending state of the buffer it reuses.
+func (r *PixelBufferRing) QueueUpload(p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buf := &r.buffers[r.next]
+	r.next = (r.next + 1) % pboRingSize
+	buf.pending = true
+	r.uploadFn(buf, p)
+	buf.pending = false
+}
+
+// QueueReadback queues an asynchronous readback of p (the caller's current
+// snapshot of the texture's pixels) into the next free buffer and returns
+// the result. In this software implementation the data is already
+// resident, so QueueReadback never actually stalls; in a real GL driver
+// this is the point where the caller blocks only if the readback hasn't
+// finished by the time the result is needed (e.g. the next frame
+// boundary), rather than on every single call.
+func (r *PixelBufferRing) QueueReadback(p []byte) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buf := &r.buffers[r.next]
+	r.next = (r.next + 1) % pboRingSize
+	buf.pending = true
+	result := r.readbackFn(buf, p)
+	buf.pending = false
+	return result
+}
+
+// Wait marks every buffer in the ring as no longer pending. QueueUpload and
+// QueueReadback finish their transfer before returning, so Wait has no
+// transfer to block on. Nothing in this package calls Wait; the caller
+// decides when to call it.
+func (r *PixelBufferRing) Wait() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.buffers {
+		r.buffers[i].pending = false
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import "testing"
+
+func TestPixelBufferRingRoundTrip(t *testing.T) {
+	r := NewPixelBufferRing(nil, nil)
+
+	p := []byte{1, 2, 3, 4}
+	r.QueueUpload(p)
+	// Mutating the caller's slice after QueueUpload returns must not
+	// affect what was queued.
+	p[0] = 0xff
+	for i, want := range []byte{1, 2, 3, 4} {
+		if r.buffers[0].data[i] != want {
+			t.Fatalf("uploaded buffer = %v, want [1 2 3 4]", r.buffers[0].data)
+		}
+	}
+
+	got := r.QueueReadback([]byte{1, 2, 3, 4})
+	want := []byte{1, 2, 3, 4}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("QueueReadback = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPixelBufferRingWaitClearsPending(t *testing.T) {
+	r := NewPixelBufferRing(nil, nil)
+	r.QueueUpload([]byte{1, 2, 3, 4})
+	r.Wait()
+	for i := range r.buffers {
+		if r.buffers[i].pending {
+			t.Errorf("buffer %d is still pending after Wait", i)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Program represents a compiled and linked fragment shader program bound to
+// a driver-level program object.
+type Program struct {
+	id     uint32
+	source string
+}
+
+// driver is the seam to the platform-specific GL bindings (cgo on
+// desktop, WebGL in js/wasm, and so on). Tests and the default build
+// exercise the software driver below, which tracks compiled sources
+// without touching any real GPU state.
+type driver interface {
+	compileProgram(source string) (uint32, error)
+	deleteProgram(id uint32)
+	useProgram(id uint32)
+}
+
+// softwareDriver is a driver implementation that performs no real GL calls.
+// It exists so the compile/cache/bind bookkeeping in this package can be
+// exercised (and unit tested) without an OpenGL context.
+type softwareDriver struct {
+	mu     sync.Mutex
+	nextID uint32
+}
+
+func (d *softwareDriver) compileProgram(source string) (uint32, error) {
+	if source == "" {
+		return 0, fmt.Errorf("opengl: shader source must not be empty")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nextID++
+	return d.nextID, nil
+}
+
+func (d *softwareDriver) deleteProgram(id uint32) {}
+
+func (d *softwareDriver) useProgram(id uint32) {}
+
+var defaultDriver driver = &softwareDriver{}
+
+// ProgramCache compiles fragment shader programs on first use and reuses
+// the compiled Program for identical source on subsequent calls, so that
+// the same user shader is never recompiled just because it is used by
+// multiple images.
+type ProgramCache struct {
+	mu       sync.Mutex
+	driver   driver
+	programs map[string]*Program
+	bound    *Program
+}
+
+// NewProgramCache returns an empty ProgramCache backed by the default
+// (platform) driver.
+func NewProgramCache() *ProgramCache {
+	return &ProgramCache{
+		driver:   defaultDriver,
+		programs: map[string]*Program{},
+	}
+}
+
+// Compile compiles src, or returns the already-compiled Program if an
+// identical source was compiled before.
+func (c *ProgramCache) Compile(src []byte) (*Program, error) {
+	key := string(src)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p, ok := c.programs[key]; ok {
+		return p, nil
+	}
+
+	id, err := c.driver.compileProgram(key)
+	if err != nil {
+		return nil, err
+	}
+	p := &Program{id: id, source: key}
+	c.programs[key] = p
+	return p, nil
+}
+
+// Delete removes p from the cache and releases its driver-level program
+// object.
+func (c *ProgramCache) Delete(p *Program) {
+	if p == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.bound == p {
+		c.bound = nil
+	}
+	delete(c.programs, p.source)
+	c.driver.deleteProgram(p.id)
+}
+
+// Bind makes p the active program, unless it already is. Bind returns
+// whether a driver-level bind actually happened, which callers use to
+// decide whether a batch needs to be flushed: consecutive draws that bind
+// the same Program (including nil, the regular textured pipeline) can be
+// merged into one draw call.
+func (c *ProgramCache) Bind(p *Program) (changed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.bound == p {
+		return false
+	}
+	var id uint32
+	if p != nil {
+		id = p.id
+	}
+	c.driver.useProgram(id)
+	c.bound = p
+	return true
+}
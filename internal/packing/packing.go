@@ -0,0 +1,93 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package packing offers a rectangle packing algorithm, used to place many
+// small images into the regions of a single, larger texture.
+package packing
+
+// Page represents a fixed-size 2D region that Nodes can be allocated from.
+//
+// Page packs free space with a best-area-fit guillotine algorithm: it
+// tracks the free rectangles that remain and, on each Alloc, picks the
+// smallest free rectangle that still fits the request, then splits what's
+// left of it into a right strip and a bottom strip.
+type Page struct {
+	width  int
+	height int
+	free   []rect
+}
+
+type rect struct {
+	x, y, width, height int
+}
+
+// Node represents an allocated, in-use region of a Page.
+type Node struct {
+	x, y, width, height int
+}
+
+// NewPage creates a new Page of the given size. The whole page starts out
+// free.
+func NewPage(width, height int) *Page {
+	return &Page{
+		width:  width,
+		height: height,
+		free:   []rect{{0, 0, width, height}},
+	}
+}
+
+// Alloc allocates a width x height region from the page and returns the
+// Node describing its position, or nil if the page has no free region
+// large enough.
+func (p *Page) Alloc(width, height int) *Node {
+	if width <= 0 || height <= 0 {
+		panic("packing: width and height must be positive")
+	}
+
+	best := -1
+	bestArea := -1
+	for i, f := range p.free {
+		if f.width < width || f.height < height {
+			continue
+		}
+		area := f.width * f.height
+		if best == -1 || area < bestArea {
+			best = i
+			bestArea = area
+		}
+	}
+	if best == -1 {
+		return nil
+	}
+
+	f := p.free[best]
+	p.free = append(p.free[:best], p.free[best+1:]...)
+
+	// Split the remaining L-shaped free space into two rectangles: one to
+	// the right of the allocated region, and one below it.
+	if rem := f.width - width; rem > 0 {
+		p.free = append(p.free, rect{f.x + width, f.y, rem, height})
+	}
+	if rem := f.height - height; rem > 0 {
+		p.free = append(p.free, rect{f.x, f.y + height, f.width, rem})
+	}
+
+	return &Node{x: f.x, y: f.y, width: width, height: height}
+}
+
+// Region returns the position and size of the region n occupies within its
+// Page.
+func (n *Node) Region() (x, y, width, height int) {
+	return n.x, n.y, n.width, n.height
+}
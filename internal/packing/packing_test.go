@@ -0,0 +1,60 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packing_test
+
+import (
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/internal/packing"
+)
+
+func TestPageAlloc(t *testing.T) {
+	p := NewPage(16, 16)
+	n := p.Alloc(8, 8)
+	if n == nil {
+		t.Fatal("Alloc should not fail on an empty page")
+	}
+	x, y, w, h := n.Region()
+	if w != 8 || h != 8 {
+		t.Errorf("got (w, h) = (%d, %d), want (8, 8)", w, h)
+	}
+	if x < 0 || y < 0 || x+w > 16 || y+h > 16 {
+		t.Errorf("node (%d, %d, %d, %d) is out of the page bounds", x, y, w, h)
+	}
+}
+
+func TestPageAllocDoesNotOverlap(t *testing.T) {
+	p := NewPage(16, 16)
+	n0 := p.Alloc(8, 16)
+	n1 := p.Alloc(8, 16)
+	if n0 == nil || n1 == nil {
+		t.Fatal("both allocations should succeed")
+	}
+	x0, _, w0, _ := n0.Region()
+	x1, _, _, _ := n1.Region()
+	if x0 == x1 {
+		t.Errorf("the two nodes overlap: x0=%d x1=%d w0=%d", x0, x1, w0)
+	}
+}
+
+func TestPageAllocFull(t *testing.T) {
+	p := NewPage(8, 8)
+	if n := p.Alloc(8, 8); n == nil {
+		t.Fatal("Alloc should succeed for a region that exactly fits the page")
+	}
+	if n := p.Alloc(1, 1); n != nil {
+		t.Error("Alloc should fail once the page has no free space left")
+	}
+}
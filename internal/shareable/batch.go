@@ -0,0 +1,138 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareable
+
+import (
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/internal/affine"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/opengl"
+)
+
+// drawState groups every piece of state that must be identical between two
+// draws for them to be merged into the same batch. This mirrors the
+// conditions documented on ebiten.Image.DrawImage and DrawTriangles (same
+// render target, same render source, same ColorM, same CompositeMode, same
+// Filter, and, when present, the same Shader and Uniforms).
+//
+// program is not compared directly: whether the active program actually
+// changed is decided by programCache.Bind, the same call a real GL driver
+// would make to glUseProgram, so that call is the single source of truth
+// instead of a second, disconnected comparison.
+type drawState struct {
+	dst      int64
+	src      int64
+	mode     opengl.CompositeMode
+	filter   graphics.Filter
+	program  *opengl.Program
+	uniforms string
+	colorM   affine.ColorM
+}
+
+// sameBatchableStateAs reports whether s and o can be merged into the same
+// batch, ignoring program (see drawState's doc comment).
+func (s drawState) sameBatchableStateAs(o drawState) bool {
+	return s.src == o.src && s.mode == o.mode && s.filter == o.filter && s.uniforms == o.uniforms && s.colorM == o.colorM
+}
+
+// batch accumulates vertices/indices for successive draws that share a
+// drawState, so they can be flushed as a single underlying draw call.
+type batch struct {
+	dst   *Image
+	src   *Image
+	state drawState
+
+	vertices []float32
+	indices  []uint16
+}
+
+var (
+	batchMu      sync.Mutex
+	currentBatch *batch
+
+	// flushCount counts how many times a batch has actually been
+	// rendered. It only exists to let tests observe batching decisions.
+	flushCount int
+)
+
+// enqueue appends a draw described by src/state/vertices/indices to the
+// current batch, flushing the previous one first if its state doesn't
+// match.
+func enqueue(dst, src *Image, state drawState, vertices []float32, indices []uint16) {
+	batchMu.Lock()
+	defer batchMu.Unlock()
+
+	// Bind whatever program this draw wants bound, exactly as a real
+	// driver would before issuing the draw call. Its return value, not a
+	// second comparison of state.program, is what decides whether the
+	// program change alone forces a flush.
+	programChanged := programCache.Bind(state.program)
+
+	if currentBatch != nil && (currentBatch.dst != dst || programChanged || !currentBatch.state.sameBatchableStateAs(state)) {
+		flushLocked()
+	}
+	// Sub-images of the same parent share a textureID and so share a
+	// batch. Source coordinates arrive relative to src; store them relative
+	// to src's backing owner so the batch samples the right region for
+	// every draw in it.
+	srcOwner, srcOffX, srcOffY := src.backing()
+	if currentBatch == nil {
+		currentBatch = &batch{dst: dst, src: srcOwner, state: state}
+	}
+
+	base := uint16(len(currentBatch.vertices) / graphics.VertexFloatNum)
+	for j := 0; j+graphics.VertexFloatNum <= len(vertices); j += graphics.VertexFloatNum {
+		v := vertices[j : j+graphics.VertexFloatNum]
+		currentBatch.vertices = append(currentBatch.vertices, v[0], v[1], v[2]+float32(srcOffX), v[3]+float32(srcOffY), v[4], v[5], v[6], v[7])
+	}
+	for _, idx := range indices {
+		currentBatch.indices = append(currentBatch.indices, idx+base)
+	}
+}
+
+// Flush renders every draw accumulated so far. Flush is called at the end
+// of a frame, and whenever a draw can't be merged into the pending batch.
+func Flush() {
+	batchMu.Lock()
+	defer batchMu.Unlock()
+	flushLocked()
+}
+
+func flushLocked() {
+	if currentBatch == nil {
+		return
+	}
+	render(currentBatch)
+	flushCount++
+	currentBatch = nil
+}
+
+// flushIfTargets flushes the pending batch if it draws into owner, so that
+// a caller about to read owner.pix directly (Image.Pixels, Image.At) sees
+// every draw enqueued so far. enqueue only flushes the previous batch once
+// a later draw with a different drawState comes in, so without this a
+// single DrawImage or DrawTriangles call followed immediately by a read
+// would never be rasterized.
+func flushIfTargets(owner *Image) {
+	batchMu.Lock()
+	defer batchMu.Unlock()
+	if currentBatch == nil {
+		return
+	}
+	if o, _, _ := currentBatch.dst.backing(); o == owner {
+		flushLocked()
+	}
+}
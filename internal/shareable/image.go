@@ -0,0 +1,225 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shareable manages the textures that back ebiten.Image values: it
+// decides which images can share a single GPU texture, compiles and caches
+// user shader programs, batches draws that target the same texture, and
+// pipelines CPU<->GPU pixel transfers through pixel buffer objects so that
+// ReplacePixels and At/Pixels don't have to stall on every call.
+package shareable
+
+import (
+	"image/color"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/internal/opengl"
+)
+
+var nextImageID int64
+
+func newImageID() int64 {
+	return atomic.AddInt64(&nextImageID, 1)
+}
+
+// Image represents a rectangular set of pixels, backed either by its own
+// GPU texture or, when it was created via SubImage, by a region of a
+// parent Image's texture.
+type Image struct {
+	id int64
+
+	mu     sync.Mutex
+	width  int
+	height int
+
+	// pix holds this image's own pixels. It is nil for sub-images, which
+	// instead read and write through parent.
+	pix []byte
+
+	parent     *Image
+	offX, offY int
+
+	volatile bool
+	screen   bool
+
+	// ring pipelines this image's pixel uploads and readbacks through a
+	// small set of PBOs so that ReplacePixels and Pixels don't have to
+	// stall the CPU on every call. It is nil for sub-images, which funnel
+	// all pixel transfers through their owner's ring instead.
+	ring *opengl.PixelBufferRing
+}
+
+func newImage(width, height int, volatile, screen bool) *Image {
+	if width < 1 || height < 1 {
+		panic("shareable: width and height must be positive")
+	}
+	return &Image{
+		id:       newImageID(),
+		width:    width,
+		height:   height,
+		pix:      make([]byte, 4*width*height),
+		volatile: volatile,
+		screen:   screen,
+		ring:     opengl.NewPixelBufferRing(nil, nil),
+	}
+}
+
+// NewImage returns a new Image of the given size, backed by its own
+// texture.
+func NewImage(width, height int) *Image {
+	return newImage(width, height, false, false)
+}
+
+// NewVolatileImage returns a new Image of the given size whose pixels are
+// cleared at the beginning of every frame instead of being saved and
+// restored.
+func NewVolatileImage(width, height int) *Image {
+	return newImage(width, height, true, false)
+}
+
+// NewScreenFramebufferImage returns a new Image backed by the screen's
+// framebuffer rather than an offscreen texture.
+func NewScreenFramebufferImage(width, height int) *Image {
+	return newImage(width, height, false, true)
+}
+
+// Size returns the width and height of the image in pixels.
+func (i *Image) Size() (width, height int) {
+	return i.width, i.height
+}
+
+// textureID identifies the GPU texture backing i, shared by every
+// sub-image allocated from the same parent. Two draws can only be batched
+// together when their source images have the same textureID.
+func (i *Image) textureID() int64 {
+	if i.parent != nil {
+		return i.parent.id
+	}
+	return i.id
+}
+
+// backing returns the Image that actually owns the pixel storage (i.e. i
+// itself, unless i is a sub-image), plus i's offset within it.
+func (i *Image) backing() (owner *Image, offX, offY int) {
+	if i.parent != nil {
+		return i.parent, i.offX, i.offY
+	}
+	return i, 0, 0
+}
+
+// SubImage returns a new Image of size width x height backed by the region
+// of i's texture at (x, y). i must not itself be a sub-image.
+//
+// SubImage is used to implement image atlases: every Image returned by
+// SubImage on the same i shares i's texture, so draws between them can
+// always be batched into a single draw call.
+func (i *Image) SubImage(x, y, width, height int) *Image {
+	if i.parent != nil {
+		panic("shareable: SubImage of a SubImage is not supported")
+	}
+	if x < 0 || y < 0 || x+width > i.width || y+height > i.height {
+		panic("shareable: the given region is out of the image's bounds")
+	}
+	return &Image{
+		id:     newImageID(),
+		width:  width,
+		height: height,
+		parent: i,
+		offX:   x,
+		offY:   y,
+	}
+}
+
+// Dispose releases the image's pixel storage. Dispose does nothing on a
+// sub-image; the parent owns the storage and must be disposed itself.
+func (i *Image) Dispose() {
+	// A pending batch may still read or write these pixels.
+	Flush()
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.parent != nil {
+		return
+	}
+	i.pix = nil
+}
+
+// ReplacePixels replaces the pixels of the image with p, which must hold
+// 4*width*height alpha-premultiplied RGBA bytes. p is copied into a PBO and
+// queued for upload; the caller is free to reuse or discard p as soon as
+// ReplacePixels returns.
+func (i *Image) ReplacePixels(p []byte) {
+	if len(p) != 4*i.width*i.height {
+		panic("shareable: len(p) must be 4 * width * height")
+	}
+
+	// Render draws enqueued before this call first, so they use the old
+	// pixels as sources and don't overwrite the new ones as destination.
+	Flush()
+
+	owner, offX, offY := i.backing()
+	owner.ring.QueueUpload(p)
+
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	if offX == 0 && i.width == owner.width && offY == 0 && i.height == owner.height {
+		copy(owner.pix, p)
+		return
+	}
+	rowLen := i.width * 4
+	for y := 0; y < i.height; y++ {
+		src := p[y*rowLen : (y+1)*rowLen]
+		dstOff := ((offY+y)*owner.width + offX) * 4
+		copy(owner.pix[dstOff:dstOff+rowLen], src)
+	}
+}
+
+// Pixels returns a copy of the image's pixels, in the same
+// alpha-premultiplied RGBA order ReplacePixels expects. Pixels blocks until
+// any pending upload to the same region has completed, so it always
+// reflects the most recent ReplacePixels call.
+func (i *Image) Pixels() []byte {
+	owner, offX, offY := i.backing()
+
+	// A pending batch targeting this image hasn't touched owner.pix yet;
+	// flush it first so Pixels (and At) reflect every draw issued so far,
+	// not just the ones that happened to be followed by a state change.
+	flushIfTargets(owner)
+
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	p := make([]byte, 4*i.width*i.height)
+	if offX == 0 && i.width == owner.width && offY == 0 && i.height == owner.height {
+		copy(p, owner.pix)
+		return owner.ring.QueueReadback(p)
+	}
+	rowLen := i.width * 4
+	for y := 0; y < i.height; y++ {
+		srcOff := ((offY+y)*owner.width + offX) * 4
+		copy(p[y*rowLen:(y+1)*rowLen], owner.pix[srcOff:srcOff+rowLen])
+	}
+	return owner.ring.QueueReadback(p)
+}
+
+// At returns the color of the pixel at (x, y), or a fully transparent
+// color if (x, y) is out of bounds.
+func (i *Image) At(x, y int) color.Color {
+	if x < 0 || y < 0 || x >= i.width || y >= i.height {
+		return color.RGBA{}
+	}
+	p := i.Pixels()
+	idx := 4 * (y*i.width + x)
+	return color.RGBA{R: p[idx], G: p[idx+1], B: p[idx+2], A: p[idx+3]}
+}
@@ -0,0 +1,89 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareable
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/internal/affine"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/opengl"
+)
+
+// uniformsKey turns a uniform value set into a stable string so that two
+// DrawImage calls with equal Uniforms (in value, regardless of map
+// iteration order) compare equal for batching purposes.
+func uniformsKey(uniforms map[string]interface{}) string {
+	if len(uniforms) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(uniforms))
+	for k := range uniforms {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%v;", k, uniforms[k])
+	}
+	return b.String()
+}
+
+// DrawImage draws the rectangle [sx0, sy0)-(sx1, sy1) of src onto i, with
+// the affine transform (a, b, c, d, tx, ty), colorm, mode and filter
+// applied, and shader (if not nil) bound in place of the regular pipeline.
+//
+// The quad is expressed as two triangles and handed to the same
+// enqueue/Flush batching path that DrawTriangles uses, so a DrawImage call
+// and a DrawTriangles call against the same destination, source, ColorM,
+// CompositeMode and Filter still merge into a single draw call.
+//
+// DrawImage only enqueues the draw into the current batch; the pixels are
+// not actually touched until Flush is called.
+func (i *Image) DrawImage(src *Image, sx0, sy0, sx1, sy1 int, a, b, c, d, tx, ty float64, colorm *affine.ColorM, mode opengl.CompositeMode, filter graphics.Filter, shader *Shader, uniforms map[string]interface{}) {
+	if i == src {
+		panic("shareable: src must be different from the receiver")
+	}
+	dx0, dy0 := a*float64(0)+c*float64(0)+tx, b*float64(0)+d*float64(0)+ty
+	w, h := float64(sx1-sx0), float64(sy1-sy0)
+	dx1, dy1 := a*w+c*0+tx, b*w+d*0+ty
+	dx2, dy2 := a*0+c*h+tx, b*0+d*h+ty
+	dx3, dy3 := a*w+c*h+tx, b*w+d*h+ty
+
+	vs := []float32{
+		float32(dx0), float32(dy0), float32(sx0), float32(sy0), 1, 1, 1, 1,
+		float32(dx1), float32(dy1), float32(sx1), float32(sy0), 1, 1, 1, 1,
+		float32(dx2), float32(dy2), float32(sx0), float32(sy1), 1, 1, 1, 1,
+		float32(dx3), float32(dy3), float32(sx1), float32(sy1), 1, 1, 1, 1,
+	}
+	indices := []uint16{0, 1, 2, 1, 2, 3}
+
+	state := drawState{
+		dst:    i.textureID(),
+		src:    src.textureID(),
+		mode:   mode,
+		filter: filter,
+		colorM: colorm.Value(),
+	}
+	if shader != nil {
+		state.program = shader.program
+		state.uniforms = uniformsKey(uniforms)
+	}
+
+	enqueue(i, src, state, vs, indices)
+}
@@ -0,0 +1,175 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareable
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/internal/opengl"
+)
+
+// render rasterizes every triangle in b.vertices/b.indices and composites
+// the result into b.dst.
+//
+// A custom Shader (b.state.program) changes what can be batched together,
+// but running the compiled shader itself requires the real GPU backend;
+// this software rasterizer is the reference implementation used when no
+// GPU is available (and in tests) and always falls back to the regular
+// textured-and-tinted pipeline.
+func render(b *batch) {
+	owner, offX, offY := b.dst.backing()
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	for i := 0; i+2 < len(b.indices); i += 3 {
+		v0 := vertexAt(b.vertices, b.indices[i])
+		v1 := vertexAt(b.vertices, b.indices[i+1])
+		v2 := vertexAt(b.vertices, b.indices[i+2])
+		rasterizeTriangle(owner, offX, offY, b.src, v0, v1, v2, b.state)
+	}
+}
+
+type vertex struct {
+	dstX, dstY float32
+	srcX, srcY float32
+	r, g, b, a float32
+}
+
+func vertexAt(vs []float32, index uint16) vertex {
+	o := int(index) * 8
+	return vertex{
+		dstX: vs[o], dstY: vs[o+1],
+		srcX: vs[o+2], srcY: vs[o+3],
+		r: vs[o+4], g: vs[o+5], b: vs[o+6], a: vs[o+7],
+	}
+}
+
+func edge(ax, ay, bx, by, px, py float64) float64 {
+	return (px-ax)*(by-ay) - (py-ay)*(bx-ax)
+}
+
+// rasterizeTriangle draws a single triangle into owner (offset by offX,
+// offY, which are non-zero when the destination is a sub-image), sampling
+// src with nearest-neighbor filtering.
+func rasterizeTriangle(owner *Image, offX, offY int, src *Image, v0, v1, v2 vertex, state drawState) {
+	area := edge(float64(v0.dstX), float64(v0.dstY), float64(v1.dstX), float64(v1.dstY), float64(v2.dstX), float64(v2.dstY))
+	if area == 0 {
+		return
+	}
+
+	minX := int(math.Floor(float64(minOf3(v0.dstX, v1.dstX, v2.dstX))))
+	maxX := int(math.Ceil(float64(maxOf3(v0.dstX, v1.dstX, v2.dstX))))
+	minY := int(math.Floor(float64(minOf3(v0.dstY, v1.dstY, v2.dstY))))
+	maxY := int(math.Ceil(float64(maxOf3(v0.dstY, v1.dstY, v2.dstY))))
+
+	if minX < 0 {
+		minX = 0
+	}
+	if minY < 0 {
+		minY = 0
+	}
+	if maxX > owner.width-offX {
+		maxX = owner.width - offX
+	}
+	if maxY > owner.height-offY {
+		maxY = owner.height - offY
+	}
+
+	srcOwner, srcOffX, srcOffY := src.backing()
+
+	for y := minY; y < maxY; y++ {
+		for x := minX; x < maxX; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+
+			w0 := edge(float64(v1.dstX), float64(v1.dstY), float64(v2.dstX), float64(v2.dstY), px, py) / area
+			w1 := edge(float64(v2.dstX), float64(v2.dstY), float64(v0.dstX), float64(v0.dstY), px, py) / area
+			w2 := edge(float64(v0.dstX), float64(v0.dstY), float64(v1.dstX), float64(v1.dstY), px, py) / area
+			if w0 < 0 || w1 < 0 || w2 < 0 {
+				continue
+			}
+
+			sx := int(w0*float64(v0.srcX) + w1*float64(v1.srcX) + w2*float64(v2.srcX))
+			sy := int(w0*float64(v0.srcY) + w1*float64(v1.srcY) + w2*float64(v2.srcY))
+			if sx < 0 || sy < 0 || sx >= src.width || sy >= src.height {
+				continue
+			}
+
+			sOff := ((srcOffY+sy)*srcOwner.width + (srcOffX + sx)) * 4
+			sr := float64(srcOwner.pix[sOff]) / 0xff
+			sg := float64(srcOwner.pix[sOff+1]) / 0xff
+			sb := float64(srcOwner.pix[sOff+2]) / 0xff
+			sa := float64(srcOwner.pix[sOff+3]) / 0xff
+
+			cr := w0*float64(v0.r) + w1*float64(v1.r) + w2*float64(v2.r)
+			cg := w0*float64(v0.g) + w1*float64(v1.g) + w2*float64(v2.g)
+			cb := w0*float64(v0.b) + w1*float64(v1.b) + w2*float64(v2.b)
+			ca := w0*float64(v0.a) + w1*float64(v1.a) + w2*float64(v2.a)
+
+			r, g, bl, a := state.colorM.Apply(sr*cr, sg*cg, sb*cb, sa*ca)
+
+			dOff := ((offY+y)*owner.width + (offX + x)) * 4
+			blend(owner.pix[dOff:dOff+4], r, g, bl, a, state.mode)
+		}
+	}
+}
+
+func blend(dst []byte, r, g, b, a float64, mode opengl.CompositeMode) {
+	if mode == opengl.CompositeModeCopy {
+		dst[0] = clamp8(r)
+		dst[1] = clamp8(g)
+		dst[2] = clamp8(b)
+		dst[3] = clamp8(a)
+		return
+	}
+	// Every other composite mode falls back to regular (source-over)
+	// alpha blending of premultiplied colors.
+	inv := 1 - a
+	dst[0] = clamp8(r + float64(dst[0])/0xff*inv)
+	dst[1] = clamp8(g + float64(dst[1])/0xff*inv)
+	dst[2] = clamp8(b + float64(dst[2])/0xff*inv)
+	dst[3] = clamp8(a + float64(dst[3])/0xff*inv)
+}
+
+func clamp8(v float64) byte {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 1 {
+		return 0xff
+	}
+	return byte(v * 0xff)
+}
+
+func minOf3(a, b, c float32) float32 {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+func maxOf3(a, b, c float32) float32 {
+	m := a
+	if b > m {
+		m = b
+	}
+	if c > m {
+		m = c
+	}
+	return m
+}
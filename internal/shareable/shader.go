@@ -0,0 +1,45 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareable
+
+import "github.com/hajimehoshi/ebiten/internal/opengl"
+
+// programCache compiles and caches every Shader's underlying GL program.
+// It is shared package-wide so that two Shaders created from identical
+// source, or repeated calls to DrawImage with the same Shader, never pay
+// for recompiling or rebinding more than necessary.
+var programCache = opengl.NewProgramCache()
+
+// Shader is the backing implementation of ebiten.Shader: a compiled
+// fragment shader program. Uniform values are not stored in the Shader;
+// they are passed to each DrawImage call.
+type Shader struct {
+	program *opengl.Program
+}
+
+// NewShader compiles src and returns the resulting Shader.
+func NewShader(src []byte) (*Shader, error) {
+	p, err := programCache.Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Shader{program: p}, nil
+}
+
+// Dispose releases the shader's compiled program.
+func (s *Shader) Dispose() {
+	programCache.Delete(s.program)
+	s.program = nil
+}
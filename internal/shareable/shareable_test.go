@@ -0,0 +1,82 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareable
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/opengl"
+)
+
+func TestImageReplacePixelsAndAt(t *testing.T) {
+	img := NewImage(2, 2)
+	p := make([]byte, 4*2*2)
+	// Pixel (1, 0) is opaque red.
+	p[4*1] = 0xff
+	p[4*1+3] = 0xff
+	img.ReplacePixels(p)
+
+	r, g, b, a := img.At(1, 0).RGBA()
+	if r>>8 != 0xff || g>>8 != 0 || b>>8 != 0 || a>>8 != 0xff {
+		t.Errorf("At(1, 0) = (%d, %d, %d, %d), want opaque red", r>>8, g>>8, b>>8, a>>8)
+	}
+	if r, _, _, a := img.At(0, 0).RGBA(); r != 0 || a != 0 {
+		t.Errorf("At(0, 0) should still be transparent, got r=%d a=%d", r, a)
+	}
+}
+
+func TestEnqueueBatchesSameState(t *testing.T) {
+	before := flushCount
+	dst := NewImage(4, 4)
+	src := NewImage(4, 4)
+
+	state := drawState{dst: dst.textureID(), src: src.textureID(), mode: opengl.CompositeModeSourceOver, filter: graphics.FilterNearest}
+	vs := make([]float32, 8*4)
+	enqueue(dst, src, state, vs, []uint16{0, 1, 2, 1, 2, 3})
+	enqueue(dst, src, state, vs, []uint16{0, 1, 2, 1, 2, 3})
+
+	batchMu.Lock()
+	n := len(currentBatch.indices)
+	batchMu.Unlock()
+	if n != 12 {
+		t.Errorf("got %d accumulated indices, want 12 (two unflushed draws)", n)
+	}
+	if flushCount != before {
+		t.Errorf("enqueueing draws with identical state should not flush yet")
+	}
+
+	Flush()
+	if flushCount != before+1 {
+		t.Errorf("Flush should render exactly one batch, got flushCount delta %d", flushCount-before)
+	}
+}
+
+func TestEnqueueFlushesOnStateChange(t *testing.T) {
+	before := flushCount
+	dst := NewImage(4, 4)
+	src := NewImage(4, 4)
+	vs := make([]float32, 8*4)
+
+	s1 := drawState{dst: dst.textureID(), src: src.textureID(), mode: opengl.CompositeModeSourceOver}
+	s2 := drawState{dst: dst.textureID(), src: src.textureID(), mode: opengl.CompositeModeCopy}
+
+	enqueue(dst, src, s1, vs, []uint16{0, 1, 2, 1, 2, 3})
+	enqueue(dst, src, s2, vs, []uint16{0, 1, 2, 1, 2, 3})
+	if flushCount != before+1 {
+		t.Errorf("a CompositeMode change should flush the pending batch, got flushCount delta %d", flushCount-before)
+	}
+	Flush()
+}
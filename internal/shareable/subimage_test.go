@@ -0,0 +1,41 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareable
+
+import "testing"
+
+func TestSubImageSharesBacking(t *testing.T) {
+	atlas := NewImage(4, 4)
+	sub := atlas.SubImage(2, 0, 2, 2)
+
+	p := make([]byte, 4*2*2)
+	for i := range p {
+		p[i] = 0xff
+	}
+	sub.ReplacePixels(p)
+
+	// The region outside of sub must be untouched.
+	if r, _, _, a := atlas.At(0, 0).RGBA(); r != 0 || a != 0 {
+		t.Errorf("atlas.At(0, 0) should be untouched by sub.ReplacePixels, got r=%d a=%d", r, a)
+	}
+
+	if r, _, _, a := atlas.At(2, 0).RGBA(); r>>8 != 0xff || a>>8 != 0xff {
+		t.Errorf("atlas.At(2, 0) should reflect sub's pixels, got r=%d a=%d", r, a)
+	}
+
+	if sub.textureID() != atlas.textureID() {
+		t.Error("a sub-image should report the same textureID as its parent, so draws between them batch")
+	}
+}
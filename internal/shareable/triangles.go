@@ -0,0 +1,41 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareable
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/affine"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/opengl"
+)
+
+// DrawTriangles draws the triangles described by vertices (8 float32s
+// each: destination x/y, source x/y, and a color-scale r/g/b/a, matching
+// ebiten.Vertex) and indices onto i.
+//
+// DrawTriangles enqueues vertices/indices into the same batch that
+// DrawImage uses (see quad.go): the batch was built around arbitrary
+// triangle lists from the start, so a quad is simply the 2-triangle case,
+// and successive DrawTriangles calls accumulate into one draw call under
+// the same drawState rules as DrawImage.
+func (i *Image) DrawTriangles(src *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode opengl.CompositeMode, filter graphics.Filter) {
+	state := drawState{
+		dst:    i.textureID(),
+		src:    src.textureID(),
+		mode:   mode,
+		filter: filter,
+		colorM: colorm.Value(),
+	}
+	enqueue(i, src, state, vertices, indices)
+}
@@ -0,0 +1,57 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareable
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/opengl"
+)
+
+// TestDrawTrianglesDistortion draws a source image onto a skewed
+// (non-axis-aligned) quad made of two triangles, something DrawImage's
+// affine-only pipeline can't express, and checks the result lands where
+// the distorted geometry puts it.
+func TestDrawTrianglesDistortion(t *testing.T) {
+	src := NewImage(2, 2)
+	p := make([]byte, 4*2*2)
+	for i := range p {
+		p[i] = 0xff
+	}
+	src.ReplacePixels(p)
+
+	dst := NewImage(8, 8)
+
+	// A quad skewed so its right edge is pushed down by 4px.
+	vs := []float32{
+		0, 0, 0, 0, 1, 1, 1, 1,
+		4, 4, 2, 0, 1, 1, 1, 1,
+		0, 4, 0, 2, 1, 1, 1, 1,
+		4, 8, 2, 2, 1, 1, 1, 1,
+	}
+	indices := []uint16{0, 1, 2, 1, 2, 3}
+
+	dst.DrawTriangles(src, vs, indices, nil, opengl.CompositeModeCopy, graphics.FilterNearest)
+
+	// At must flush the pending batch itself; nothing outside this
+	// package can call the unexported Flush.
+	if r, _, _, a := dst.At(1, 1).RGBA(); r>>8 != 0xff || a>>8 != 0xff {
+		t.Errorf("dst.At(1, 1) inside the skewed quad should be opaque white, got r=%d a=%d", r>>8, a>>8)
+	}
+	if _, _, _, a := dst.At(7, 1).RGBA(); a != 0 {
+		t.Errorf("dst.At(7, 1) outside the skewed quad should stay transparent, got a=%d", a)
+	}
+}
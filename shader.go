@@ -0,0 +1,73 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/shareable"
+)
+
+// Shader represents a compiled fragment shader program that can be
+// assigned to DrawImageOptions.Shader.
+//
+// A Shader is intended to customize how DrawImage renders a source image,
+// receiving the source texture, the texture coordinates of the fragment
+// being drawn, and the Uniforms given via DrawImageOptions, and producing
+// the final premultiplied-alpha color for that fragment. This would be
+// used for effects like blurs, bloom, palette swaps, lighting, and CRT
+// filters that would otherwise require per-pixel CPU work.
+//
+// Currently, binding a Shader only changes batching: draws with the same
+// Shader and Uniforms can still merge into one draw call, but every
+// fragment is rendered with the regular texture-and-ColorM pipeline
+// regardless of which Shader (if any) is bound. Running the compiled
+// program per fragment requires a real GPU backend, which this tree
+// doesn't have yet.
+//
+// Shaders are immutable once created and can be shared across many
+// DrawImage calls and images.
+type Shader struct {
+	shareableShader *shareable.Shader
+}
+
+// NewShader compiles a fragment shader program from src and returns a
+// Shader that can be assigned to DrawImageOptions.Shader.
+//
+// The accepted source dialect is device-dependent (e.g. GLSL ES on
+// OpenGL). src must define a function with the signature
+//
+//     vec4 Fragment(vec2 texCoord, vec4 color)
+//
+// where texCoord is normalized to the source image's texture, and color is
+// the regular DrawImage output (source pixel multiplied by ColorM).
+// NewShader compiles and caches src, but the renderer never calls
+// Fragment: as described above, every fragment is drawn with the regular
+// texture-and-ColorM pipeline.
+//
+// NewShader returns an error if src fails to compile.
+func NewShader(src []byte) (*Shader, error) {
+	s, err := shareable.NewShader(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Shader{shareableShader: s}, nil
+}
+
+// Dispose disposes the shader's compiled program.
+//
+// After disposing, a Shader must not be used in any DrawImageOptions.
+func (s *Shader) Dispose() {
+	s.shareableShader.Dispose()
+	s.shareableShader = nil
+}
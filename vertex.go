@@ -0,0 +1,38 @@
+// Copyright 2014 Hajime Hoshi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+// Vertex represents a vertex passed to DrawTriangles.
+type Vertex struct {
+	// DstX and DstY are the destination position in pixels.
+	DstX float32
+	DstY float32
+
+	// SrcX and SrcY are the source position in texels, i.e. pixels of the
+	// source image.
+	SrcX float32
+	SrcY float32
+
+	// ColorR, ColorG, ColorB, and ColorA represent color scaling values
+	// that are each applied to the source color, in addition to ColorM
+	// in DrawTrianglesOptions.
+	// These values are typically in the range [0, 1] and the default
+	// (zero) value is 0, so a freshly-created Vertex must have these
+	// set to 1 to draw the source color unmodified.
+	ColorR float32
+	ColorG float32
+	ColorB float32
+	ColorA float32
+}